@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"time"
 	"unsafe"
@@ -88,7 +89,7 @@ func (h *RustTracingLogrusHook) Fire(entry *logrus.Entry) error {
 	}
 
 	// Call Rust callback
-	cMsg := C.CString(string(buf))
+	cMsg := C.CString(escapeNUL(string(buf)))
 	C.call_rust_log_callback(callback, C.int(rustLevel), cMsg)
 	C.free(unsafe.Pointer(cMsg))
 
@@ -115,21 +116,38 @@ func (w *RustTracingWriter) Write(p []byte) (n int, err error) {
 	}
 
 	// Call Rust callback with info level
-	cMsg := C.CString(msg)
+	cMsg := C.CString(escapeNUL(msg))
 	C.call_rust_log_callback(callback, C.int(LogLevelInfo), cMsg)
 	C.free(unsafe.Pointer(cMsg))
 
 	return len(p), nil
 }
 
+// escapeNUL replaces embedded NUL bytes with a visible "\x00" escape.
+// C.CString does not reject NUL, so the C side would otherwise see the
+// message silently truncated at the first one.
+func escapeNUL(s string) string {
+	return strings.ReplaceAll(s, "\x00", `\x00`)
+}
+
 // Global callback management
 var (
 	rustLogCallback unsafe.Pointer
 	callbackMu      sync.RWMutex
+
+	// setupMu serializes gvproxy_set_log_callback so concurrent calls cannot
+	// interleave the logrus/log reconfiguration and leave mixed state.
+	setupMu sync.Mutex
+	// hookRegistered is guarded by setupMu. The hook reads rustLogCallback on
+	// every entry, so it only needs to be registered once.
+	hookRegistered bool
 )
 
 //export gvproxy_set_log_callback
 func gvproxy_set_log_callback(callback unsafe.Pointer) {
+	setupMu.Lock()
+	defer setupMu.Unlock()
+
 	callbackMu.Lock()
 	rustLogCallback = callback
 	callbackMu.Unlock()
@@ -142,7 +160,10 @@ func gvproxy_set_log_callback(callback unsafe.Pointer) {
 			DisableColors:    true,
 		})
 		logrus.SetOutput(io.Discard) // Discard direct output, only use hook to forward to Rust
-		logrus.AddHook(&RustTracingLogrusHook{})
+		if !hookRegistered {
+			logrus.AddHook(&RustTracingLogrusHook{})
+			hookRegistered = true
+		}
 
 		// Redirect standard log package to Rust tracing (for vendored code like tcpproxy)
 		log.SetOutput(&RustTracingWriter{})
@@ -188,6 +209,51 @@ type GvproxyConfig struct {
 	CaptureFile      *string       `json:"capture_file,omitempty"`
 }
 
+// validateNoNUL rejects string fields containing NUL bytes.
+// The config arrives as a C string, but JSON "\u0000" escapes still decode to
+// raw NULs. Most such values would fail later with an opaque parse or syscall
+// error, and a bad DNS zone default_ip would silently become a nil IP, so fail
+// early with an error naming the field instead.
+func (c *GvproxyConfig) validateNoNUL() error {
+	check := func(field, value string) error {
+		if i := strings.IndexByte(value, 0); i >= 0 {
+			return fmt.Errorf("config field %s contains NUL byte at offset %d", field, i)
+		}
+		return nil
+	}
+
+	for _, f := range []struct{ field, value string }{
+		{"subnet", c.Subnet},
+		{"gateway_ip", c.GatewayIP},
+		{"gateway_mac", c.GatewayMac},
+		{"guest_ip", c.GuestIP},
+		{"guest_mac", c.GuestMac},
+	} {
+		if err := check(f.field, f.value); err != nil {
+			return err
+		}
+	}
+	if c.CaptureFile != nil {
+		if err := check("capture_file", *c.CaptureFile); err != nil {
+			return err
+		}
+	}
+	for i, zone := range c.DNSZones {
+		if err := check(fmt.Sprintf("dns_zones[%d].name", i), zone.Name); err != nil {
+			return err
+		}
+		if err := check(fmt.Sprintf("dns_zones[%d].default_ip", i), zone.DefaultIP); err != nil {
+			return err
+		}
+	}
+	for i, domain := range c.DNSSearchDomains {
+		if err := check(fmt.Sprintf("dns_search_domains[%d]", i), domain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GvproxyInstance tracks a running gvisor-tap-vsock instance
 type GvproxyInstance struct {
 	ID         int64
@@ -216,6 +282,11 @@ func gvproxy_create(configJSON *C.char) C.longlong {
 		return -1
 	}
 
+	if err := config.validateNoNUL(); err != nil {
+		logrus.WithError(err).Error("Invalid gvproxy config")
+		return -1
+	}
+
 	instancesMu.Lock()
 	id := nextID
 	nextID++
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"unsafe"
+
+	logrus "github.com/sirupsen/logrus"
+)
+
+func TestEscapeNUL(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"no NUL", "hello world", "hello world"},
+		{"one NUL", "a\x00b", `a\x00b`},
+		{"several NULs", "\x00a\x00\x00b", `\x00a\x00\x00b`},
+		{"trailing NUL", "abc\x00", `abc\x00`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := escapeNUL(tt.in)
+			if got != tt.want {
+				t.Errorf("escapeNUL(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if strings.IndexByte(got, 0) >= 0 {
+				t.Errorf("escapeNUL(%q) still contains a NUL byte", tt.in)
+			}
+		})
+	}
+}
+
+func TestGvproxyConfigValidateNoNUL(t *testing.T) {
+	tests := []struct {
+		name      string
+		json      string
+		wantField string // empty means the config is valid
+	}{
+		{
+			name: "valid",
+			json: `{"subnet":"192.168.127.0/24","gateway_ip":"192.168.127.1","capture_file":"/tmp/a.pcap",` +
+				`"dns_zones":[{"name":"local.","default_ip":"192.168.127.2"}],"dns_search_domains":["local"]}`,
+		},
+		{
+			name:      "capture_file",
+			json:      `{"capture_file":"/tmp/a\u0000b"}`,
+			wantField: "capture_file",
+		},
+		{
+			name:      "subnet",
+			json:      `{"subnet":"192.168.127.0\u0000/24"}`,
+			wantField: "subnet",
+		},
+		{
+			name:      "gateway_ip",
+			json:      `{"gateway_ip":"192.168.127.1\u0000"}`,
+			wantField: "gateway_ip",
+		},
+		{
+			name:      "gateway_mac",
+			json:      `{"gateway_mac":"5a:94:ef:e4:0c:dd\u0000"}`,
+			wantField: "gateway_mac",
+		},
+		{
+			name:      "guest_ip",
+			json:      `{"guest_ip":"192.168.127.2\u0000"}`,
+			wantField: "guest_ip",
+		},
+		{
+			name:      "guest_mac",
+			json:      `{"guest_mac":"5a:94:ef:e4:0c:ee\u0000"}`,
+			wantField: "guest_mac",
+		},
+		{
+			name:      "dns zone name",
+			json:      `{"dns_zones":[{"name":"ok."},{"name":"bad\u0000."}]}`,
+			wantField: "dns_zones[1].name",
+		},
+		{
+			name:      "dns zone default_ip",
+			json:      `{"dns_zones":[{"name":"local.","default_ip":"192.168.127.2\u0000"}]}`,
+			wantField: "dns_zones[0].default_ip",
+		},
+		{
+			name:      "dns search domain",
+			json:      `{"dns_search_domains":["\u0000local"]}`,
+			wantField: "dns_search_domains[0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config GvproxyConfig
+			if err := json.Unmarshal([]byte(tt.json), &config); err != nil {
+				t.Fatalf("json.Unmarshal: %v", err)
+			}
+
+			err := config.validateNoNUL()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("validateNoNUL() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("validateNoNUL() = nil, want error naming %s", tt.wantField)
+			}
+			if !strings.Contains(err.Error(), tt.wantField) {
+				t.Errorf("validateNoNUL() = %q, want it to name %s", err, tt.wantField)
+			}
+		})
+	}
+}
+
+// castAs reinterprets p as a *T, with T inferred from a typed nil. Test files
+// cannot use cgo, so this is how a Go buffer is passed where a *C.char is expected.
+func castAs[T any](_ *T, p unsafe.Pointer) *T {
+	return (*T)(p)
+}
+
+func TestGvproxyCreateRejectsNUL(t *testing.T) {
+	configJSON := []byte(`{"subnet":"192.168.127.0/24","capture_file":"/tmp/a\u0000b"}` + "\x00")
+	cConfig := castAs(gvproxy_get_socket_path(0), unsafe.Pointer(&configJSON[0]))
+
+	instancesMu.RLock()
+	wantNextID, wantInstances := nextID, len(instances)
+	instancesMu.RUnlock()
+
+	if id := gvproxy_create(cConfig); id != -1 {
+		t.Fatalf("gvproxy_create() = %d, want -1", id)
+	}
+
+	instancesMu.RLock()
+	defer instancesMu.RUnlock()
+	if nextID != wantNextID || len(instances) != wantInstances {
+		t.Errorf("gvproxy_create() allocated an instance for a rejected config")
+	}
+}
+
+func TestGvproxySetLogCallbackRegistersHookOnce(t *testing.T) {
+	// Never invoked: nothing below logs while the callback is set.
+	var fakeCallback byte
+	t.Cleanup(func() { gvproxy_set_log_callback(nil) })
+
+	gvproxy_set_log_callback(unsafe.Pointer(&fakeCallback))
+	gvproxy_set_log_callback(unsafe.Pointer(&fakeCallback))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				gvproxy_set_log_callback(nil)
+			} else {
+				gvproxy_set_log_callback(unsafe.Pointer(&fakeCallback))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := len(logrus.StandardLogger().Hooks[logrus.InfoLevel]); got != 1 {
+		t.Errorf("registered %d logrus hooks, want 1", got)
+	}
+}